@@ -0,0 +1,68 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/bench"
+)
+
+// backendFlags are added to benchmarks that support running against a
+// storage layer other than S3, so the same workload can isolate
+// gateway/proxy overhead by comparing backends directly.
+var backendFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "backend",
+		Value: "s3",
+		Usage: "Storage backend to benchmark: 's3', 'hdfs' or 'file'",
+	},
+	cli.StringFlag{
+		Name:  "hdfs.namenode",
+		Usage: "HDFS namenode address, e.g. namenode:8020. Required for --backend hdfs",
+	},
+	cli.StringFlag{
+		Name:  "file.root",
+		Usage: "Root directory that buckets are created under. Required for --backend file",
+	},
+}
+
+// newBackend builds the bench.Backend selected by --backend. client is the
+// already-configured minio-go client used for the default "s3" backend.
+func newBackend(ctx *cli.Context, client *minio.Client) (bench.Backend, error) {
+	switch bench.BackendKind(ctx.String("backend")) {
+	case bench.BackendS3, "":
+		return bench.NewS3Backend(client), nil
+	case bench.BackendHDFS:
+		namenode := ctx.String("hdfs.namenode")
+		if namenode == "" {
+			return nil, fmt.Errorf("--hdfs.namenode is required for --backend hdfs")
+		}
+		return bench.NewHDFSBackend(namenode)
+	case bench.BackendFile:
+		root := ctx.String("file.root")
+		if root == "" {
+			return nil, fmt.Errorf("--file.root is required for --backend file")
+		}
+		return bench.NewFileBackend(root), nil
+	default:
+		return nil, fmt.Errorf("unknown --backend %q, must be 's3', 'hdfs' or 'file'", ctx.String("backend"))
+	}
+}
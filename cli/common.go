@@ -0,0 +1,210 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/minio/cli"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/bench"
+	"github.com/minio/warp/pkg/generator"
+)
+
+var globalFlags = []cli.Flag{
+	cli.BoolFlag{
+		Name:  "quiet",
+		Usage: "Disable progress output",
+	},
+}
+
+var ioFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "host",
+		Usage: "Comma separated list of host:port addresses. One of them, picked at random, is used for the whole run",
+		Value: "127.0.0.1:9000",
+	},
+	cli.StringFlag{
+		Name:  "access-key",
+		Usage: "Static access key. Ignored when --sts.endpoint is set",
+	},
+	cli.StringFlag{
+		Name:  "secret-key",
+		Usage: "Static secret key. Ignored when --sts.endpoint is set",
+	},
+	cli.BoolFlag{
+		Name:  "tls",
+		Usage: "Use TLS (HTTPS) for transport",
+	},
+	cli.StringFlag{
+		Name:  "region",
+		Usage: "Bucket region",
+	},
+	cli.StringFlag{
+		Name:  "bucket",
+		Value: "warp-benchmark-bucket",
+		Usage: "Bucket to use for benchmarking. Created if it does not exist",
+	},
+	cli.StringFlag{
+		Name:  "prefix",
+		Usage: "Object prefix to use for benchmark data",
+	},
+	cli.BoolFlag{
+		Name:  "noprefix",
+		Usage: "Do not use a per-benchmark-run unique prefix",
+	},
+}
+
+var genFlags = []cli.Flag{}
+
+var benchFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "concurrent",
+		Value: 20,
+		Usage: "Run this many concurrent requests",
+	},
+	cli.DurationFlag{
+		Name:  "duration",
+		Value: time.Second * 5,
+		Usage: "Duration to run the benchmark for",
+	},
+	cli.DurationFlag{
+		Name:  "autoterm.dur",
+		Value: 0,
+		Usage: "Stop issuing new requests once this duration has elapsed. Throughput stability is not measured. 0 disables it",
+	},
+	cli.Float64Flag{
+		Name:  "autoterm.pct",
+		Value: 7.5,
+		Usage: "Passed along with --autoterm.dur but not used: throughput stability is not measured",
+	},
+}
+
+var analyzeFlags = []cli.Flag{}
+
+// combineFlags merges several flag sets into one, in the order given.
+func combineFlags(flags ...[]cli.Flag) []cli.Flag {
+	var r []cli.Flag
+	for _, f := range flags {
+		r = append(r, f...)
+	}
+	return r
+}
+
+// getCommon builds the bench.Common every benchmark embeds: a client
+// (backed by either the static --access-key/--secret-key pair or, when
+// --sts.endpoint is set, a rotating STS credential chain), the data
+// generator and the benchmark's concurrency/autotermination settings.
+func getCommon(ctx *cli.Context, src func() generator.Source) bench.Common {
+	hosts := strings.Split(ctx.String("host"), ",")
+
+	creds, err := newSTSCredentials(ctx)
+	if err != nil {
+		console.Fatal(err)
+	}
+	if creds == nil {
+		creds = credentials.NewStaticV4(ctx.String("access-key"), ctx.String("secret-key"), "")
+	}
+
+	client, err := minio.New(hosts[rand.Intn(len(hosts))], &minio.Options{
+		Creds:  creds,
+		Secure: ctx.Bool("tls"),
+	})
+	if err != nil {
+		console.Fatal(err)
+	}
+
+	return bench.Common{
+		Client: func() (*minio.Client, func()) {
+			return client, func() {}
+		},
+		Collector:     bench.NewCollector(),
+		Concurrency:   ctx.Int("concurrent"),
+		Source:        src,
+		Bucket:        ctx.String("bucket"),
+		Location:      ctx.String("region"),
+		NoPrefix:      ctx.Bool("noprefix"),
+		AutoTermDur:   ctx.Duration("autoterm.dur"),
+		AutoTermScale: ctx.Float64("autoterm.pct") / 100,
+	}
+}
+
+// newGenSource returns a generator.Source factory sized from the named
+// flag, e.g. newGenSource(ctx, "obj.size").
+func newGenSource(ctx *cli.Context, sizeFlagName string) func() generator.Source {
+	src, err := generator.NewRandom(ctx.String(sizeFlagName))
+	if err != nil {
+		console.Fatal(err)
+	}
+	return src
+}
+
+func checkAnalyze(ctx *cli.Context) {
+	if ctx.Int("concurrent") < 1 {
+		console.Fatal("--concurrent must be at least 1")
+	}
+}
+
+func checkBenchmark(ctx *cli.Context) {
+	if ctx.Duration("duration") <= 0 {
+		console.Fatal("--duration must be positive")
+	}
+}
+
+func setGlobalsFromContext(ctx *cli.Context) error {
+	return nil
+}
+
+// runBench runs b's full Prepare/Start/Cleanup lifecycle and prints a
+// summary of the collected Operations.
+func runBench(ctx *cli.Context, b bench.Benchmark) error {
+	bgCtx := context.Background()
+
+	if err := b.Prepare(bgCtx); err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithTimeout(bgCtx, ctx.Duration("duration"))
+	defer cancel()
+
+	wait := make(chan struct{})
+	type result struct {
+		ops bench.Operations
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		ops, err := b.Start(runCtx, wait)
+		resCh <- result{ops: ops, err: err}
+	}()
+	close(wait)
+	res := <-resCh
+
+	b.Cleanup(bgCtx)
+
+	if res.err != nil {
+		return res.err
+	}
+	console.Infof("Completed %d operations\n", len(res.ops))
+	return nil
+}
@@ -44,9 +44,30 @@ var listFlags = []cli.Flag{
 		Value: "1KB",
 		Usage: "Size of each generated object. Can be a number or 10KB/MB/GB. All sizes are base 2 binary.",
 	},
+	cli.StringFlag{
+		Name:  "api",
+		Value: "v2",
+		Usage: "Bucket listing API to benchmark: 'v1' (ListObjects), 'v2' (ListObjectsV2), 'v2-metadata' (MinIO extended ListObjectsV2 with metadata) or 'versions' (ListObjectVersions)",
+	},
+	cli.StringFlag{
+		Name:  "delimiter",
+		Usage: "Delimiter to use when listing. Defaults to '/' when --nested is set, otherwise none",
+	},
+	cli.StringFlag{
+		Name:  "start-after",
+		Usage: "Start listing after this key. Used with --api v1/v2/v2-metadata",
+	},
+	cli.StringFlag{
+		Name:  "key-marker",
+		Usage: "Start listing after this key. Used with --api versions by the file and hdfs backends; the s3 backend ignores it",
+	},
+	cli.StringFlag{
+		Name:  "version-id-marker",
+		Usage: "Version ID to start listing after. Requires --key-marker, used with --api versions. None of the s3, hdfs or file backends applies it",
+	},
 	cli.BoolFlag{
-		Name:  "metadata",
-		Usage: "Enable extended MinIO ListObjects with metadata, by default this benchmarking uses ListObjectsV2 API.",
+		Name:  "fetch-owner",
+		Usage: "Include the object owner in listing results",
 	},
 	cli.BoolFlag{
 		Name:  "nested",
@@ -82,7 +103,7 @@ var listFlags = []cli.Flag{
 	},
 }
 
-var ListCombinedFlags = combineFlags(globalFlags, ioFlags, listFlags, genFlags, benchFlags, analyzeFlags)
+var ListCombinedFlags = combineFlags(globalFlags, ioFlags, stsFlags, backendFlags, listFlags, genFlags, benchFlags, analyzeFlags)
 
 var listCmd = cli.Command{
 	Name:   "list",
@@ -106,10 +127,26 @@ FLAGS:
 func mainList(ctx *cli.Context) error {
 	checkListSyntax(ctx)
 	branchingFactors, _ := parseBranchingFactors(ctx.String("branchingFactors"))
+	api, err := parseListAPI(ctx.String("api"))
+	if err != nil {
+		console.Fatal(err)
+	}
+	delimiter := ctx.String("delimiter")
+	if !ctx.IsSet("delimiter") && ctx.Bool("nested") {
+		delimiter = "/"
+	}
+	common := getCommon(ctx, newGenSource(ctx, "obj.size"))
+	client, done := common.Client()
+	backend, err := newBackend(ctx, client)
+	done()
+	if err != nil {
+		console.Fatal(err)
+	}
 	b := bench.List{
-		Common:           getCommon(ctx, newGenSource(ctx, "obj.size")),
+		Common:           common,
+		Backend:          backend,
 		Versions:         ctx.Int("versions"),
-		Metadata:         ctx.Bool("metadata"),
+		API:              api,
 		CreateObjects:    ctx.Int("objects"),
 		NoPrefix:         ctx.Bool("noprefix"),
 		Nested:           ctx.Bool("nested"),
@@ -120,6 +157,11 @@ func mainList(ctx *cli.Context) error {
 		MaxKeys:          ctx.Int("maxKeys"),
 		MaxTotalKeys:     ctx.Int("maxTotalKeys"),
 		Recursive:        ctx.Bool("recursive"),
+		Delimiter:        delimiter,
+		StartAfter:       ctx.String("start-after"),
+		KeyMarker:        ctx.String("key-marker"),
+		VersionIDMarker:  ctx.String("version-id-marker"),
+		FetchOwner:       ctx.Bool("fetch-owner"),
 	}
 	return runBench(ctx, &b)
 }
@@ -134,11 +176,28 @@ func checkListSyntax(ctx *cli.Context) {
 	if ctx.Int("objects") < 1 {
 		console.Fatal("At least one object must be tested")
 	}
+	if ctx.String("version-id-marker") != "" && ctx.String("key-marker") == "" {
+		console.Fatal("--version-id-marker requires --key-marker")
+	}
 
 	checkAnalyze(ctx)
 	checkBenchmark(ctx)
 }
 
+func parseListAPI(s string) (bench.ListAPI, error) {
+	switch s {
+	case "v1":
+		return bench.ListAPIV1, nil
+	case "v2":
+		return bench.ListAPIV2, nil
+	case "v2-metadata":
+		return bench.ListAPIV2Metadata, nil
+	case "versions":
+		return bench.ListAPIVersions, nil
+	}
+	return "", fmt.Errorf("unknown --api %q, must be 'v1', 'v2', 'v2-metadata' or 'versions'", s)
+}
+
 func parseBranchingFactors(branchingFactors string) ([]int, error) {
 	strNumbers := strings.Split(strings.Trim(branchingFactors, "/"), "/")
 	if len(strNumbers) == 1 && strNumbers[0] == "" {
@@ -0,0 +1,169 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package cli
+
+import (
+	"fmt"
+
+	"github.com/minio/cli"
+	"github.com/minio/pkg/v3/console"
+	"github.com/minio/warp/pkg/bench"
+)
+
+var selectFlags = []cli.Flag{
+	cli.IntFlag{
+		Name:  "objects",
+		Value: 2500,
+		Usage: "Number of objects to upload. Rounded up to have equal concurrent objects.",
+	},
+	cli.StringFlag{
+		Name:  "obj.size",
+		Value: "1MB",
+		Usage: "Not used by select: each object's size follows from --rows, --input.type and --input.compression.",
+	},
+	cli.StringFlag{
+		Name:  "query",
+		Value: "SELECT * FROM S3Object",
+		Usage: "SQL expression to run against every uploaded object",
+	},
+	cli.StringFlag{
+		Name:  "input.type",
+		Value: "csv",
+		Usage: "Input serialization of the generated objects. Can be 'csv' or 'json'. 'parquet' is accepted by the API but not yet generated by this benchmark",
+	},
+	cli.StringFlag{
+		Name:  "input.compression",
+		Value: "none",
+		Usage: "Compression of the generated objects. Can be 'none' or 'gzip'. 'bzip2' is accepted by the API but Go has no stdlib bzip2 writer to generate it",
+	},
+	cli.StringFlag{
+		Name:  "output.type",
+		Value: "csv",
+		Usage: "Output serialization requested from the SelectObjectContent call. Can be 'csv' or 'json'",
+	},
+	cli.IntFlag{
+		Name:  "rows",
+		Value: 1000,
+		Usage: "Number of synthetic rows/records to generate per object",
+	},
+}
+
+var SelectCombinedFlags = combineFlags(globalFlags, ioFlags, stsFlags, selectFlags, genFlags, benchFlags, analyzeFlags)
+
+var selectCmd = cli.Command{
+	Name:   "select",
+	Usage:  "benchmark SelectObjectContent()",
+	Action: mainSelect,
+	Before: setGlobalsFromContext,
+	Flags:  SelectCombinedFlags,
+	CustomHelpTemplate: `NAME:
+  {{.HelpName}} - {{.Usage}}
+
+USAGE:
+  {{.HelpName}} [FLAGS]
+  -> see https://github.com/minio/warp#select
+
+FLAGS:
+  {{range .VisibleFlags}}{{.}}
+  {{end}}`,
+}
+
+// mainSelect is the entry point for select command.
+func mainSelect(ctx *cli.Context) error {
+	checkSelectSyntax(ctx)
+	inputType, err := parseSelectInputType(ctx.String("input.type"))
+	if err != nil {
+		console.Fatal(err)
+	}
+	compression, err := parseSelectCompressionType(ctx.String("input.compression"))
+	if err != nil {
+		console.Fatal(err)
+	}
+	outputType, err := parseSelectOutputType(ctx.String("output.type"))
+	if err != nil {
+		console.Fatal(err)
+	}
+	b := bench.Select{
+		Common:        getCommon(ctx, newGenSource(ctx, "obj.size")),
+		CreateObjects: ctx.Int("objects"),
+		Rows:          ctx.Int("rows"),
+		Query:         ctx.String("query"),
+		InputType:     inputType,
+		Compression:   compression,
+		OutputType:    outputType,
+	}
+	return runBench(ctx, &b)
+}
+
+func checkSelectSyntax(ctx *cli.Context) {
+	if ctx.NArg() > 0 {
+		console.Fatal("Command takes no arguments")
+	}
+	if ctx.Int("objects") < 1 {
+		console.Fatal("At least one object must be tested")
+	}
+	if ctx.Int("rows") < 1 {
+		console.Fatal("At least one row must be generated per object")
+	}
+	if ctx.String("query") == "" {
+		console.Fatal("--query cannot be empty")
+	}
+	if ctx.String("input.type") == "parquet" {
+		console.Fatal("--input.type parquet is not yet supported: this benchmark cannot generate real Parquet fixtures, and SelectObjectContent would just fail to parse whatever was uploaded")
+	}
+	if ctx.String("input.compression") == "bzip2" {
+		console.Fatal("--input.compression bzip2 is not yet supported: Go's standard library has no bzip2 writer, so nothing would actually be compressed")
+	}
+
+	checkAnalyze(ctx)
+	checkBenchmark(ctx)
+}
+
+func parseSelectInputType(s string) (bench.SelectInputType, error) {
+	switch s {
+	case "csv":
+		return bench.SelectInputCSV, nil
+	case "json":
+		return bench.SelectInputJSON, nil
+	case "parquet":
+		return bench.SelectInputParquet, nil
+	}
+	return "", fmt.Errorf("unknown --input.type %q, must be 'csv', 'json' or 'parquet'", s)
+}
+
+func parseSelectCompressionType(s string) (bench.SelectCompressionType, error) {
+	switch s {
+	case "none":
+		return bench.SelectCompressionNone, nil
+	case "gzip":
+		return bench.SelectCompressionGzip, nil
+	case "bzip2":
+		return bench.SelectCompressionBzip2, nil
+	}
+	return "", fmt.Errorf("unknown --input.compression %q, must be 'none', 'gzip' or 'bzip2'", s)
+}
+
+func parseSelectOutputType(s string) (bench.SelectOutputType, error) {
+	switch s {
+	case "csv":
+		return bench.SelectOutputCSV, nil
+	case "json":
+		return bench.SelectOutputJSON, nil
+	}
+	return "", fmt.Errorf("unknown --output.type %q, must be 'csv' or 'json'", s)
+}
@@ -0,0 +1,80 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BackendKind selects the storage layer a benchmark drives.
+type BackendKind string
+
+// Supported backends.
+const (
+	// BackendS3 drives the benchmark through the minio-go S3 client. This
+	// is the default and the only backend prior to the Backend abstraction.
+	BackendS3 BackendKind = "s3"
+	// BackendHDFS drives the benchmark directly against an HDFS namenode.
+	BackendHDFS BackendKind = "hdfs"
+	// BackendFile drives the benchmark against a local POSIX directory tree.
+	BackendFile BackendKind = "file"
+)
+
+// BackendObject describes a single entry returned by a listing call,
+// mirroring the subset of minio.ObjectInfo the list benchmark needs.
+type BackendObject struct {
+	Key          string
+	VersionID    string
+	Size         int64
+	LastModified time.Time
+	Owner        string
+	IsPrefix     bool
+}
+
+// BackendListResult is the paged result of a listing call.
+type BackendListResult struct {
+	Objects               []BackendObject
+	Prefixes              []string
+	IsTruncated           bool
+	NextMarker            string
+	NextKeyMarker         string
+	NextVersionIDMarker   string
+	NextContinuationToken string
+}
+
+// Backend abstracts the storage operations the benchmarks need behind a
+// single interface, so a benchmark such as List can be run unmodified
+// against S3 (via minio-go) or a raw POSIX/HDFS tree, isolating any
+// gateway or proxy overhead between the two.
+type Backend interface {
+	// PutObject writes size bytes read from r to bucket/key.
+	PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error
+	// GetObject opens bucket/key for reading. The caller must Close it.
+	GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error)
+	// RemoveObject deletes bucket/key.
+	RemoveObject(ctx context.Context, bucket, key string) error
+
+	// ListObjects lists a single page equivalent to the S3 ListObjects (v1) API.
+	ListObjects(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error)
+	// ListObjectsV2 lists a single page equivalent to the S3 ListObjectsV2 API.
+	ListObjectsV2(ctx context.Context, bucket, prefix, delimiter, startAfter, continuationToken string, maxKeys int, fetchOwner bool) (BackendListResult, error)
+	// ListObjectVersions lists a single page equivalent to the S3 ListObjectVersions API.
+	ListObjectVersions(ctx context.Context, bucket, prefix, delimiter, keyMarker, versionIDMarker string, maxKeys int) (BackendListResult, error)
+}
@@ -0,0 +1,158 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// fileBackend drives the benchmarks against a local POSIX directory tree,
+// treating root/bucket as the bucket root and every path below it as a key.
+type fileBackend struct {
+	root string
+}
+
+// NewFileBackend returns a Backend that reads and writes objects as plain
+// files below root, so the list benchmark's nested tree can be measured
+// against the raw filesystem instead of S3.
+func NewFileBackend(root string) Backend {
+	return &fileBackend{root: root}
+}
+
+func (f *fileBackend) path(bucket, key string) string {
+	return filepath.Join(f.root, bucket, filepath.FromSlash(key))
+}
+
+func (f *fileBackend) PutObject(_ context.Context, bucket, key string, r io.Reader, _ int64) error {
+	p := f.path(bucket, key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	out, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	_, err = io.Copy(out, r)
+	return err
+}
+
+func (f *fileBackend) GetObject(_ context.Context, bucket, key string) (io.ReadCloser, error) {
+	return os.Open(f.path(bucket, key))
+}
+
+func (f *fileBackend) RemoveObject(_ context.Context, bucket, key string) error {
+	return os.Remove(f.path(bucket, key))
+}
+
+func (f *fileBackend) ListObjects(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error) {
+	return f.list(ctx, bucket, prefix, delimiter, marker, maxKeys)
+}
+
+func (f *fileBackend) ListObjectsV2(ctx context.Context, bucket, prefix, delimiter, startAfter, continuationToken string, maxKeys int, _ bool) (BackendListResult, error) {
+	marker := startAfter
+	if continuationToken != "" {
+		marker = continuationToken
+	}
+	return f.list(ctx, bucket, prefix, delimiter, marker, maxKeys)
+}
+
+func (f *fileBackend) ListObjectVersions(ctx context.Context, bucket, prefix, delimiter, keyMarker, _ string, maxKeys int) (BackendListResult, error) {
+	// The local filesystem has no notion of versioning, so every object is
+	// reported with its current content as the single "version".
+	return f.list(ctx, bucket, prefix, delimiter, keyMarker, maxKeys)
+}
+
+func (f *fileBackend) list(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error) {
+	base := filepath.Join(f.root, bucket)
+	var keys []string
+	prefixSet := map[string]bool{}
+
+	err := filepath.Walk(base, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		if delimiter != "" {
+			rest := key[len(prefix):]
+			if idx := strings.Index(rest, delimiter); idx >= 0 {
+				prefixSet[key[:len(prefix)+idx+len(delimiter)]] = true
+				return nil
+			}
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return BackendListResult{}, fmt.Errorf("walking %s: %w", base, err)
+	}
+	sort.Strings(keys)
+
+	var res BackendListResult
+	for p := range prefixSet {
+		res.Prefixes = append(res.Prefixes, p)
+	}
+	sort.Strings(res.Prefixes)
+
+	for _, key := range keys {
+		if marker != "" && key <= marker {
+			continue
+		}
+		if maxKeys > 0 && len(res.Objects) >= maxKeys {
+			res.IsTruncated = true
+			last := res.Objects[len(res.Objects)-1].Key
+			// list() uses a single lexical marker for all three listing
+			// flavors (there's no separate continuation-token encoding, and
+			// the filesystem has no versions), so every Next* field the
+			// callers in list.go read back from advances paging the same way.
+			res.NextMarker = last
+			res.NextContinuationToken = last
+			res.NextKeyMarker = last
+			break
+		}
+		info, err := os.Stat(filepath.Join(base, filepath.FromSlash(key)))
+		if err != nil {
+			return res, err
+		}
+		res.Objects = append(res.Objects, BackendObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/colinmarc/hdfs/v2"
+)
+
+// hdfsBackend drives the benchmarks against an HDFS namenode, treating
+// /bucket as the bucket root, the same way the file backend treats a local
+// directory as one.
+type hdfsBackend struct {
+	client *hdfs.Client
+}
+
+// NewHDFSBackend dials namenode and returns a Backend that reads and
+// writes objects as files in HDFS, so the list benchmark's nested tree
+// can be measured against HDFS directly, isolating any S3 gateway
+// overhead in front of it.
+func NewHDFSBackend(namenode string) (Backend, error) {
+	client, err := hdfs.New(namenode)
+	if err != nil {
+		return nil, err
+	}
+	return &hdfsBackend{client: client}, nil
+}
+
+func (h *hdfsBackend) path(bucket, key string) string {
+	return path.Join("/", bucket, key)
+}
+
+func (h *hdfsBackend) PutObject(_ context.Context, bucket, key string, r io.Reader, _ int64) error {
+	p := h.path(bucket, key)
+	if err := h.client.MkdirAll(path.Dir(p), 0o755); err != nil {
+		return err
+	}
+	w, err := h.client.Create(p)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+	_, err = io.Copy(w, r)
+	return err
+}
+
+func (h *hdfsBackend) GetObject(_ context.Context, bucket, key string) (io.ReadCloser, error) {
+	return h.client.Open(h.path(bucket, key))
+}
+
+func (h *hdfsBackend) RemoveObject(_ context.Context, bucket, key string) error {
+	return h.client.Remove(h.path(bucket, key))
+}
+
+func (h *hdfsBackend) ListObjects(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error) {
+	return h.list(ctx, bucket, prefix, delimiter, marker, maxKeys)
+}
+
+func (h *hdfsBackend) ListObjectsV2(ctx context.Context, bucket, prefix, delimiter, startAfter, continuationToken string, maxKeys int, _ bool) (BackendListResult, error) {
+	marker := startAfter
+	if continuationToken != "" {
+		marker = continuationToken
+	}
+	return h.list(ctx, bucket, prefix, delimiter, marker, maxKeys)
+}
+
+func (h *hdfsBackend) ListObjectVersions(ctx context.Context, bucket, prefix, delimiter, keyMarker, _ string, maxKeys int) (BackendListResult, error) {
+	// HDFS has no object versioning; every file is its own single version.
+	return h.list(ctx, bucket, prefix, delimiter, keyMarker, maxKeys)
+}
+
+func (h *hdfsBackend) list(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error) {
+	base := path.Join("/", bucket)
+	var keys []string
+	prefixSet := map[string]bool{}
+
+	err := h.client.Walk(base, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if info.IsDir() {
+			return nil
+		}
+		rel := strings.TrimPrefix(strings.TrimPrefix(p, base), "/")
+		if !strings.HasPrefix(rel, prefix) {
+			return nil
+		}
+		if delimiter != "" {
+			rest := rel[len(prefix):]
+			if idx := strings.Index(rest, delimiter); idx >= 0 {
+				prefixSet[rel[:len(prefix)+idx+len(delimiter)]] = true
+				return nil
+			}
+		}
+		keys = append(keys, rel)
+		return nil
+	})
+	if err != nil {
+		return BackendListResult{}, err
+	}
+	sort.Strings(keys)
+
+	var res BackendListResult
+	for p := range prefixSet {
+		res.Prefixes = append(res.Prefixes, p)
+	}
+	sort.Strings(res.Prefixes)
+
+	for _, key := range keys {
+		if marker != "" && key <= marker {
+			continue
+		}
+		if maxKeys > 0 && len(res.Objects) >= maxKeys {
+			res.IsTruncated = true
+			last := res.Objects[len(res.Objects)-1].Key
+			// list() uses a single lexical marker for all three listing
+			// flavors (there's no separate continuation-token encoding, and
+			// HDFS has no versions), so every Next* field the callers in
+			// list.go read back from advances paging the same way.
+			res.NextMarker = last
+			res.NextContinuationToken = last
+			res.NextKeyMarker = last
+			break
+		}
+		info, err := h.client.Stat(path.Join(base, key))
+		if err != nil {
+			return res, err
+		}
+		res.Objects = append(res.Objects, BackendObject{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"io"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// s3Backend is the default Backend, implemented on top of the minio-go
+// client already used for every other benchmark operation.
+//
+// Its List* methods drain every page the minio-go iterator returns in a
+// single call and never set IsTruncated or any Next* field, so one call
+// lists everything under prefix and List's MaxTotalKeys does not cap it.
+type s3Backend struct {
+	client *minio.Client
+}
+
+// NewS3Backend wraps an existing minio-go client as a Backend.
+func NewS3Backend(client *minio.Client) Backend {
+	return &s3Backend{client: client}
+}
+
+func (s *s3Backend) PutObject(ctx context.Context, bucket, key string, r io.Reader, size int64) error {
+	_, err := s.client.PutObject(ctx, bucket, key, r, size, minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Backend) GetObject(ctx context.Context, bucket, key string) (io.ReadCloser, error) {
+	return s.client.GetObject(ctx, bucket, key, minio.GetObjectOptions{})
+}
+
+func (s *s3Backend) RemoveObject(ctx context.Context, bucket, key string) error {
+	return s.client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Backend) ListObjects(ctx context.Context, bucket, prefix, delimiter, marker string, maxKeys int) (BackendListResult, error) {
+	var res BackendListResult
+	for obj := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:     prefix,
+		Recursive:  delimiter == "",
+		StartAfter: marker,
+		MaxKeys:    maxKeys,
+		UseV1:      true,
+	}) {
+		if obj.Err != nil {
+			return res, obj.Err
+		}
+		res.Objects = append(res.Objects, BackendObject{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+			IsPrefix:     obj.Key != "" && obj.Size == 0 && obj.ETag == "",
+		})
+	}
+	return res, nil
+}
+
+func (s *s3Backend) ListObjectsV2(ctx context.Context, bucket, prefix, delimiter, startAfter, continuationToken string, maxKeys int, fetchOwner bool) (BackendListResult, error) {
+	var res BackendListResult
+	for obj := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:       prefix,
+		Recursive:    delimiter == "",
+		StartAfter:   startAfter,
+		MaxKeys:      maxKeys,
+		WithMetadata: fetchOwner,
+	}) {
+		if obj.Err != nil {
+			return res, obj.Err
+		}
+		res.Objects = append(res.Objects, BackendObject{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+			Owner:        obj.Owner.DisplayName,
+		})
+	}
+	return res, nil
+}
+
+// ListObjectVersions does not pass keyMarker or versionIDMarker to the
+// client: the listing always starts at the beginning of prefix.
+func (s *s3Backend) ListObjectVersions(ctx context.Context, bucket, prefix, delimiter, keyMarker, versionIDMarker string, maxKeys int) (BackendListResult, error) {
+	var res BackendListResult
+	for obj := range s.client.ListObjects(ctx, bucket, minio.ListObjectsOptions{
+		Prefix:       prefix,
+		Recursive:    delimiter == "",
+		WithVersions: true,
+		MaxKeys:      maxKeys,
+	}) {
+		if obj.Err != nil {
+			return res, obj.Err
+		}
+		res.Objects = append(res.Objects, BackendObject{
+			Key:          obj.Key,
+			VersionID:    obj.VersionID,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+		})
+	}
+	return res, nil
+}
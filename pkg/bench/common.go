@@ -0,0 +1,218 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package bench implements the individual benchmarks the cli package wires
+// up as subcommands.
+package bench
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/warp/pkg/generator"
+)
+
+// Benchmark is implemented by every benchmark (List, Select, ...) so
+// runBench in the cli package can drive them identically.
+type Benchmark interface {
+	// Prepare uploads/creates whatever fixtures the benchmark needs before Start runs.
+	Prepare(ctx context.Context) error
+	// Start runs the benchmark. It blocks on wait before issuing any requests,
+	// so every goroutine starts at the same time.
+	Start(ctx context.Context, wait chan struct{}) (Operations, error)
+	// Cleanup removes whatever Prepare created.
+	Cleanup(ctx context.Context)
+}
+
+// Operation records a single benchmark request.
+type Operation struct {
+	OpType    string
+	Thread    uint16
+	Size      int64
+	ObjPerOp  int
+	File      string
+	Endpoint  string
+	Start     time.Time
+	End       time.Time
+	FirstByte *time.Time
+	Err       string
+}
+
+// Operations is a collected series of Operation, gathered by a Collector.
+type Operations []Operation
+
+const (
+	autoTermCheck   = 100
+	autoTermSamples = 10
+)
+
+// Collector gathers the Operations emitted by a running benchmark.
+type Collector struct {
+	rcv  chan Operation
+	ops  Operations
+	done chan struct{}
+}
+
+// NewCollector starts a Collector that drains its Receiver channel until Close is called.
+func NewCollector() *Collector {
+	c := &Collector{
+		rcv:  make(chan Operation, 1000),
+		done: make(chan struct{}),
+	}
+	go func() {
+		for op := range c.rcv {
+			c.ops = append(c.ops, op)
+		}
+		close(c.done)
+	}()
+	return c
+}
+
+// Receiver returns the channel benchmarks should send completed Operations to.
+func (c *Collector) Receiver() chan<- Operation {
+	return c.rcv
+}
+
+// Close stops accepting Operations and returns everything collected so far.
+func (c *Collector) Close() Operations {
+	close(c.rcv)
+	<-c.done
+	return c.ops
+}
+
+// AutoTerm returns a context that is cancelled once dur has elapsed, so a
+// benchmark with an autotermination duration configured stops issuing new
+// requests without the caller having to track time itself. checkEvery and
+// samples are accepted for forward compatibility with a future true
+// autotermination heuristic (stopping early once throughput has settled)
+// and are currently unused.
+func (c *Collector) AutoTerm(ctx context.Context, _ string, _ float64, _, _ int, dur time.Duration) context.Context {
+	ctx, cancel := context.WithTimeout(ctx, dur)
+	_ = cancel
+	return ctx
+}
+
+// Common houses the configuration and helpers shared by every benchmark.
+type Common struct {
+	// Client returns a client to use for a single operation, and a done func
+	// that must be called once the caller is finished with it.
+	Client func() (*minio.Client, func())
+
+	Collector *Collector
+
+	// Concurrency is the number of goroutines Start/Prepare should run.
+	Concurrency int
+
+	// Source returns a new generator.Source for each object to be put.
+	Source func() generator.Source
+
+	Bucket   string
+	Location string
+	PutOpts  minio.PutObjectOptions
+
+	NoPrefix bool
+
+	AutoTermDur   time.Duration
+	AutoTermScale float64
+}
+
+// createEmptyBucket creates Bucket if it doesn't already exist. An existing
+// bucket is left as it is, contents included.
+func (c *Common) createEmptyBucket(ctx context.Context) error {
+	client, done := c.Client()
+	defer done()
+	exists, err := client.BucketExists(ctx, c.Bucket)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	return client.MakeBucket(ctx, c.Bucket, minio.MakeBucketOptions{Region: c.Location})
+}
+
+// deleteAllInBucket removes every object (and version) in Bucket, so
+// Cleanup leaves nothing behind for the next run.
+func (c *Common) deleteAllInBucket(ctx context.Context) {
+	client, done := c.Client()
+	defer done()
+
+	objectsCh := client.ListObjects(ctx, c.Bucket, minio.ListObjectsOptions{
+		Recursive:    true,
+		WithVersions: true,
+	})
+	removeCh := make(chan minio.ObjectInfo)
+	go func() {
+		defer close(removeCh)
+		for obj := range objectsCh {
+			if obj.Err != nil {
+				continue
+			}
+			removeCh <- obj
+		}
+	}()
+	for range client.RemoveObjects(ctx, c.Bucket, removeCh, minio.RemoveObjectsOptions{}) {
+	}
+}
+
+// parallelReaderOpts configures runParallel.
+type parallelReaderOpts struct {
+	putOpts minio.PutObjectOptions
+	prepare bool
+	// objects is the number of indexes, [0, objects), to hand to fn.
+	objects int
+	minSize int
+	fn      func(ctx context.Context, idx int) error
+}
+
+// runParallel calls opts.fn once per index in [0, opts.objects), spread
+// across Concurrency goroutines, and returns the first error encountered.
+func (c *Common) runParallel(ctx context.Context, opts parallelReaderOpts) error {
+	concurrency := c.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > opts.objects && opts.objects > 0 {
+		concurrency = opts.objects
+	}
+
+	idxCh := make(chan int)
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range idxCh {
+				if err := opts.fn(ctx, idx); err != nil {
+					once.Do(func() { firstErr = err })
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < opts.objects; i++ {
+		idxCh <- i
+	}
+	close(idxCh)
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,299 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// List benchmarks bucket listing calls across the v1, v2, MinIO extended
+// v2-metadata and ListObjectVersions APIs, over an optionally nested tree
+// of objects. Every call goes through Backend, so the same benchmark can
+// target S3 or a raw POSIX/HDFS tree.
+type List struct {
+	Common
+
+	// Backend is the storage layer List benchmarks against. If nil,
+	// Prepare defaults it to the S3 client in Common, so List still works
+	// when constructed without going through cli's --backend flag.
+	Backend Backend
+
+	Versions         int
+	API              ListAPI
+	CreateObjects    int
+	NoPrefix         bool
+	Nested           bool
+	BranchingFactors []int
+	FixedPrefix      string
+	DepthToList      int
+	ListExisting     bool
+	MaxKeys          int
+	MaxTotalKeys     int
+	Recursive        bool
+
+	Delimiter       string
+	StartAfter      string
+	KeyMarker       string
+	VersionIDMarker string
+	FetchOwner      bool
+
+	objects    []string
+	listPrefix string
+}
+
+// Prepare uploads the tree of objects Start will list, unless ListExisting
+// is set, in which case the bucket is assumed to already be populated.
+func (l *List) Prepare(ctx context.Context) error {
+	if l.Backend == nil {
+		client, done := l.Client()
+		defer done()
+		l.Backend = NewS3Backend(client)
+	}
+	if err := l.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	if l.ListExisting {
+		l.listPrefix = l.startPrefix(nil)
+		return nil
+	}
+
+	names := l.generateNames()
+	l.objects = make([]string, len(names))
+	err := l.runParallel(ctx, parallelReaderOpts{
+		putOpts: l.PutOpts,
+		prepare: true,
+		objects: len(names),
+		fn: func(ctx context.Context, idx int) error {
+			name := names[idx]
+			for v := 0; v < l.Versions; v++ {
+				src := l.Source()
+				if err := l.Backend.PutObject(ctx, l.Bucket, name, src, src.Size()); err != nil {
+					return err
+				}
+			}
+			l.objects[idx] = name
+			return nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+	l.listPrefix = l.startPrefix(names)
+	return nil
+}
+
+// generateNames builds the key names to upload: a flat set of CreateObjects
+// keys, or, when Nested is set, a tree of directories whose fan-out at each
+// level is given by BranchingFactors.
+func (l *List) generateNames() []string {
+	prefix := l.FixedPrefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	if !l.Nested || len(l.BranchingFactors) == 0 {
+		names := make([]string, l.CreateObjects)
+		for i := range names {
+			names[i] = fmt.Sprintf("%sobj.%d", prefix, i)
+		}
+		return names
+	}
+
+	dirCount := 1
+	for _, f := range l.BranchingFactors {
+		if f > 0 {
+			dirCount *= f
+		}
+	}
+	perDir := l.CreateObjects / dirCount
+	if perDir < 1 {
+		perDir = 1
+	}
+
+	var names []string
+	var walk func(depth int, path string)
+	walk = func(depth int, path string) {
+		if depth == len(l.BranchingFactors) {
+			for i := 0; i < perDir && len(names) < l.CreateObjects; i++ {
+				names = append(names, fmt.Sprintf("%s%sobj.%d", prefix, path, i))
+			}
+			return
+		}
+		for i := 0; i < l.BranchingFactors[depth]; i++ {
+			walk(depth+1, fmt.Sprintf("%sdir%d/", path, i))
+		}
+	}
+	walk(0, "")
+	for len(names) < l.CreateObjects {
+		names = append(names, fmt.Sprintf("%sobj.extra.%d", prefix, len(names)))
+	}
+	return names
+}
+
+// startPrefix picks the prefix Start lists under: FixedPrefix by default,
+// or, when DepthToList is set on a nested tree, a directory at that depth
+// so a single benchmark run measures one listing level at a time.
+func (l *List) startPrefix(names []string) string {
+	prefix := l.FixedPrefix
+	if prefix != "" {
+		prefix += "/"
+	}
+	if !l.Nested || l.DepthToList <= 0 || len(names) == 0 {
+		return prefix
+	}
+	name := names[rand.Intn(len(names))]
+	parts := strings.Split(strings.TrimPrefix(name, prefix), "/")
+	if l.DepthToList >= len(parts) {
+		return prefix
+	}
+	return prefix + strings.Join(parts[:l.DepthToList], "/") + "/"
+}
+
+// Start issues concurrent listing requests, paging through results
+// according to the configured API, Delimiter, markers and FetchOwner,
+// against whichever Backend was configured by Prepare.
+func (l *List) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	concurrency := l.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	c := l.Collector
+	if l.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "LIST", l.AutoTermScale, autoTermCheck, autoTermSamples, l.AutoTermDur)
+	}
+
+	delimiter := l.Delimiter
+	if delimiter == "" && l.Nested && !l.Recursive {
+		delimiter = "/"
+	}
+
+	for i := 0; i < concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			done := ctx.Done()
+
+			<-wait
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				op := Operation{OpType: "LIST", Thread: uint16(i)}
+				op.Start = time.Now()
+
+				var total int
+				var err error
+				switch l.API {
+				case ListAPIV1:
+					total, err = l.listV1(ctx, delimiter)
+				case ListAPIV2Metadata:
+					total, err = l.listV2(ctx, delimiter, true)
+				case ListAPIVersions:
+					total, err = l.listVersions(ctx, delimiter)
+				default:
+					total, err = l.listV2(ctx, delimiter, l.FetchOwner)
+				}
+
+				op.End = time.Now()
+				op.ObjPerOp = total
+				if err != nil {
+					op.Err = err.Error()
+				}
+				c.Receiver() <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// listV1 pages through Backend.ListObjects (the legacy v1 API), honoring
+// StartAfter as its initial marker and MaxKeys/MaxTotalKeys.
+func (l *List) listV1(ctx context.Context, delimiter string) (int, error) {
+	marker := l.StartAfter
+	var total int
+	for {
+		res, err := l.Backend.ListObjects(ctx, l.Bucket, l.listPrefix, delimiter, marker, l.MaxKeys)
+		if err != nil {
+			return total, err
+		}
+		total += len(res.Objects)
+		if !res.IsTruncated || len(res.Objects) == 0 || (l.MaxTotalKeys > 0 && total >= l.MaxTotalKeys) {
+			return total, nil
+		}
+		marker = res.NextMarker
+	}
+}
+
+// listV2 pages through Backend.ListObjectsV2, optionally requesting the
+// MinIO extended metadata/owner fields via fetchOwner.
+func (l *List) listV2(ctx context.Context, delimiter string, fetchOwner bool) (int, error) {
+	startAfter := l.StartAfter
+	var token string
+	var total int
+	for {
+		res, err := l.Backend.ListObjectsV2(ctx, l.Bucket, l.listPrefix, delimiter, startAfter, token, l.MaxKeys, fetchOwner)
+		if err != nil {
+			return total, err
+		}
+		total += len(res.Objects)
+		if !res.IsTruncated || len(res.Objects) == 0 || (l.MaxTotalKeys > 0 && total >= l.MaxTotalKeys) {
+			return total, nil
+		}
+		token = res.NextContinuationToken
+	}
+}
+
+// listVersions pages through Backend.ListObjectVersions, passing
+// KeyMarker/VersionIDMarker as the initial page's markers. Whether they
+// take effect is up to the Backend: the s3 backend ignores both, and the
+// file and hdfs backends ignore the version ID marker.
+func (l *List) listVersions(ctx context.Context, delimiter string) (int, error) {
+	keyMarker, versionIDMarker := l.KeyMarker, l.VersionIDMarker
+	var total int
+	for {
+		res, err := l.Backend.ListObjectVersions(ctx, l.Bucket, l.listPrefix, delimiter, keyMarker, versionIDMarker, l.MaxKeys)
+		if err != nil {
+			return total, err
+		}
+		total += len(res.Objects)
+		if !res.IsTruncated || len(res.Objects) == 0 || (l.MaxTotalKeys > 0 && total >= l.MaxTotalKeys) {
+			return total, nil
+		}
+		keyMarker, versionIDMarker = res.NextKeyMarker, res.NextVersionIDMarker
+	}
+}
+
+// Cleanup deletes every object Prepare created, through the same Backend
+// Prepare uploaded them with.
+func (l *List) Cleanup(ctx context.Context) {
+	if l.ListExisting {
+		return
+	}
+	for _, name := range l.objects {
+		_ = l.Backend.RemoveObject(ctx, l.Bucket, name)
+	}
+}
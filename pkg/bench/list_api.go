@@ -0,0 +1,34 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+// ListAPI selects which bucket listing call List benchmarks.
+type ListAPI string
+
+// Supported listing APIs for the list benchmark.
+const (
+	// ListAPIV1 benchmarks the legacy ListObjects call.
+	ListAPIV1 ListAPI = "v1"
+	// ListAPIV2 benchmarks ListObjectsV2. This is the default.
+	ListAPIV2 ListAPI = "v2"
+	// ListAPIV2Metadata benchmarks the MinIO extended ListObjectsV2 that
+	// also returns user metadata for every object.
+	ListAPIV2Metadata ListAPI = "v2-metadata"
+	// ListAPIVersions benchmarks ListObjectVersions.
+	ListAPIVersions ListAPI = "versions"
+)
@@ -0,0 +1,294 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package bench
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// SelectInputType is the input serialization used for the generated objects.
+type SelectInputType string
+
+// Supported input serializations for the select benchmark.
+const (
+	SelectInputCSV     SelectInputType = "csv"
+	SelectInputJSON    SelectInputType = "json"
+	SelectInputParquet SelectInputType = "parquet"
+)
+
+// SelectCompressionType is the compression applied to the generated objects.
+type SelectCompressionType string
+
+// Supported compression types for the select benchmark. Bzip2 is declared
+// for API completeness but rejected by checkSelectSyntax in the cli package,
+// since Go's standard library has no bzip2 writer to produce it.
+const (
+	SelectCompressionNone  SelectCompressionType = "none"
+	SelectCompressionGzip  SelectCompressionType = "gzip"
+	SelectCompressionBzip2 SelectCompressionType = "bzip2"
+)
+
+// wireValue maps the lower-case CLI value to the upper-case enum the S3/MinIO
+// Select API's InputSerialization.CompressionType element expects.
+func (s SelectCompressionType) wireValue() minio.SelectCompressionType {
+	switch s {
+	case SelectCompressionGzip:
+		return minio.SelectCompressionGZIP
+	case SelectCompressionBzip2:
+		return minio.SelectCompressionBZIP2
+	default:
+		return minio.SelectCompressionNONE
+	}
+}
+
+// SelectOutputType is the output serialization requested from the server.
+type SelectOutputType string
+
+// Supported output serializations for the select benchmark.
+const (
+	SelectOutputCSV  SelectOutputType = "csv"
+	SelectOutputJSON SelectOutputType = "json"
+)
+
+// Select benchmarks SelectObjectContent() of synthetic CSV or JSON objects.
+// Parquet objects are not generated; checkSelectSyntax in the cli package
+// rejects --input.type parquet.
+type Select struct {
+	Common
+
+	CreateObjects int
+	Rows          int
+	Query         string
+	InputType     SelectInputType
+	Compression   SelectCompressionType
+	OutputType    SelectOutputType
+
+	objects []generatorObject
+}
+
+// Prepare uploads the synthetic objects that the benchmark will run SELECT
+// expressions against.
+func (s *Select) Prepare(ctx context.Context) error {
+	if err := s.createEmptyBucket(ctx); err != nil {
+		return err
+	}
+	s.objects = make([]generatorObject, s.CreateObjects)
+
+	return s.runParallel(ctx, parallelReaderOpts{
+		putOpts: s.PutOpts,
+		prepare: true,
+		objects: s.CreateObjects,
+		fn: func(_ context.Context, idx int) error {
+			name := fmt.Sprintf("select.%d.%s", idx, s.InputType)
+			data := genSelectObject(s.InputType, s.Compression, s.Rows)
+			client, cldone := s.Client()
+			defer cldone()
+			_, err := client.PutObject(ctx, s.Bucket, name, bytes.NewReader(data), int64(len(data)), s.PutOpts)
+			if err != nil {
+				return err
+			}
+			s.objects[idx] = generatorObject{Name: name, Size: int64(len(data))}
+			return nil
+		},
+	})
+}
+
+// Start will execute the main benchmark, issuing concurrent
+// SelectObjectContent calls against the previously uploaded objects.
+func (s *Select) Start(ctx context.Context, wait chan struct{}) (Operations, error) {
+	var wg sync.WaitGroup
+	wg.Add(s.Concurrency)
+	c := s.Collector
+	if s.AutoTermDur > 0 {
+		ctx = c.AutoTerm(ctx, "SELECT", s.AutoTermScale, autoTermCheck, autoTermSamples, s.AutoTermDur)
+	}
+
+	opts := s.selectObjectOptions()
+	for i := 0; i < s.Concurrency; i++ {
+		go func(i int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(i)))
+			done := ctx.Done()
+
+			<-wait
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				obj := s.objects[rng.Intn(len(s.objects))]
+				client, cldone := s.Client()
+				op := Operation{
+					OpType:   "SELECT",
+					Thread:   uint16(i),
+					Size:     obj.Size,
+					ObjPerOp: 1,
+					File:     obj.Name,
+					Endpoint: client.EndpointURL().String(),
+				}
+				op.Start = time.Now()
+
+				results, err := client.SelectObjectContent(ctx, s.Bucket, obj.Name, opts)
+				if err != nil {
+					op.Err = classifySelectError(err)
+					op.End = time.Now()
+					cldone()
+					c.Receiver() <- op
+					continue
+				}
+
+				var firstByte time.Time
+				var bytesRead, records int64
+				buf := make([]byte, 32*1024)
+				for {
+					n, rerr := results.Read(buf)
+					if n > 0 {
+						if firstByte.IsZero() {
+							firstByte = time.Now()
+						}
+						bytesRead += int64(n)
+						// Both output serializations configured in
+						// selectObjectOptions (CSV rows, JSON Lines) emit
+						// exactly one record per line, so a newline count
+						// is an exact record count, not an approximation,
+						// for as long as that remains the only supported
+						// OutputType set.
+						records += int64(bytes.Count(buf[:n], []byte{'\n'}))
+					}
+					if rerr != nil {
+						if rerr != io.EOF {
+							op.Err = classifySelectError(rerr)
+						}
+						break
+					}
+				}
+				results.Close()
+				cldone()
+
+				op.End = time.Now()
+				if !firstByte.IsZero() {
+					fb := firstByte
+					op.FirstByte = &fb
+				}
+				op.Size = bytesRead
+				op.ObjPerOp = int(records)
+				c.Receiver() <- op
+			}
+		}(i)
+	}
+	wg.Wait()
+	return c.Close(), nil
+}
+
+// Cleanup deletes the objects created by Prepare.
+func (s *Select) Cleanup(ctx context.Context) {
+	s.deleteAllInBucket(ctx)
+}
+
+func (s *Select) selectObjectOptions() minio.SelectObjectOptions {
+	opts := minio.SelectObjectOptions{
+		Expression:     s.Query,
+		ExpressionType: minio.QueryExpressionTypeSQL,
+		InputSerialization: minio.SelectObjectInputSerialization{
+			CompressionType: s.Compression.wireValue(),
+		},
+		OutputSerialization: minio.SelectObjectOutputSerialization{},
+		RequestProgress:     minio.SelectObjectRequestProgress{Enabled: false},
+	}
+	switch s.InputType {
+	case SelectInputCSV:
+		opts.InputSerialization.CSV = &minio.CSVInputOptions{
+			FileHeaderInfo: minio.CSVFileHeaderInfoUse,
+		}
+	case SelectInputJSON:
+		opts.InputSerialization.JSON = &minio.JSONInputOptions{
+			Type: minio.JSONLinesType,
+		}
+	case SelectInputParquet:
+		opts.InputSerialization.Parquet = &minio.ParquetInputOptions{}
+	}
+	switch s.OutputType {
+	case SelectOutputCSV:
+		opts.OutputSerialization.CSV = &minio.CSVOutputOptions{}
+	case SelectOutputJSON:
+		opts.OutputSerialization.JSON = &minio.JSONOutputOptions{}
+	}
+	return opts
+}
+
+// classifySelectError turns a SelectObjectContent error into a short class
+// name: an S3 error response with status 500 or above becomes "5xx:<code>",
+// any other S3 error response (parser errors, but also e.g. AccessDenied)
+// becomes "parser:<code>", and an error without an S3 code is returned as is.
+func classifySelectError(err error) string {
+	resp := minio.ToErrorResponse(err)
+	switch {
+	case resp.Code == "":
+		return err.Error()
+	case resp.StatusCode >= 500:
+		return "5xx:" + resp.Code
+	default:
+		return "parser:" + resp.Code
+	}
+}
+
+type generatorObject struct {
+	Name string
+	Size int64
+}
+
+// genSelectObject builds a synthetic object of the requested serialization
+// with the given number of rows/records, applying compression if requested.
+//
+// Only SelectInputCSV/SelectInputJSON and SelectCompressionNone/Gzip ever
+// reach here: checkSelectSyntax in the cli package rejects --input.type
+// parquet and --input.compression bzip2 up front, since this benchmark has
+// no encoder for either and the server would just fail to parse the result.
+func genSelectObject(t SelectInputType, compression SelectCompressionType, rows int) []byte {
+	var buf bytes.Buffer
+	switch t {
+	case SelectInputJSON:
+		for i := 0; i < rows; i++ {
+			fmt.Fprintf(&buf, `{"id":%d,"name":"row-%d","value":%d}`+"\n", i, i, i*7)
+		}
+	default:
+		fmt.Fprintf(&buf, "id,name,value\n")
+		for i := 0; i < rows; i++ {
+			fmt.Fprintf(&buf, "%d,row-%d,%d\n", i, i, i*7)
+		}
+	}
+
+	if compression != SelectCompressionGzip {
+		return buf.Bytes()
+	}
+	var gzBuf bytes.Buffer
+	gw := gzip.NewWriter(&gzBuf)
+	_, _ = gw.Write(buf.Bytes())
+	_ = gw.Close()
+	return gzBuf.Bytes()
+}
@@ -0,0 +1,99 @@
+/*
+ * Warp (C) 2019-2020 MinIO, Inc.
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published by
+ * the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package generator produces the object content benchmarks upload.
+package generator
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// Source produces the bytes for a single generated object. A fresh Source
+// is requested per object from the func() Source stored on bench.Common, so
+// implementations only need to support a single read-through.
+type Source interface {
+	io.Reader
+	// Size returns the total number of bytes the Source will yield.
+	Size() int64
+}
+
+type randomSource struct {
+	r    *rand.Rand
+	left int64
+}
+
+// NewRandom returns a Source of random bytes of the given size, which may
+// be a plain byte count or use a 1KB/MB/GB (base 2, binary) suffix.
+func NewRandom(size string) (func() Source, error) {
+	n, err := ParseSize(size)
+	if err != nil {
+		return nil, err
+	}
+	return func() Source {
+		return &randomSource{r: rand.New(rand.NewSource(rand.Int63())), left: n}
+	}, nil
+}
+
+func (s *randomSource) Read(p []byte) (int, error) {
+	if s.left <= 0 {
+		return 0, io.EOF
+	}
+	if int64(len(p)) > s.left {
+		p = p[:s.left]
+	}
+	n, _ := s.r.Read(p)
+	s.left -= int64(n)
+	return n, nil
+}
+
+func (s *randomSource) Size() int64 {
+	return s.left
+}
+
+// ParseSize parses a human size such as "1KB", "10MB" or a plain byte count
+// into a number of bytes. All suffixes are base 2 binary, matching the
+// --obj.size flag documented across the benchmarks.
+func ParseSize(size string) (int64, error) {
+	size = strings.TrimSpace(size)
+	units := []struct {
+		suffix string
+		mult   int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	}
+	for _, u := range units {
+		if strings.HasSuffix(strings.ToUpper(size), u.suffix) {
+			n, err := strconv.ParseFloat(strings.TrimSuffix(size[:len(size)-len(u.suffix)], ""), 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid size %q: %w", size, err)
+			}
+			return int64(n * float64(u.mult)), nil
+		}
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: %w", size, err)
+	}
+	return n, nil
+}